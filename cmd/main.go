@@ -4,16 +4,32 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
+	"github.com/anandpulakala/InMemoryService/internal/grpcserver"
 	"github.com/anandpulakala/InMemoryService/internal/response_time"
 )
 
-// Global service instance for handling response time operations
-var svc = response_time.NewService()
+// Global registry instance for handling response time operations. It is
+// assigned in main() once the storage backend has been selected. Samples
+// stored without labels live in the series identified by the empty label
+// set.
+var reg *response_time.Registry
 
 // storeRequest represents the JSON payload for storing a response time.
 // The timestamp should be in RFC3339 format, and duration_ms should be
@@ -23,6 +39,11 @@ type storeRequest struct {
 	Timestamp string `json:"timestamp"`
 	// DurationMs is the response time in milliseconds
 	DurationMs int64 `json:"duration_ms"`
+	// Labels optionally tags this sample as belonging to its own series,
+	// e.g. {"route": "/checkout", "method": "GET", "tenant": "acme"}.
+	// Samples with no labels share the series identified by the empty
+	// label set.
+	Labels map[string]string `json:"labels"`
 }
 
 // storeResponse represents the JSON response for a successful store operation.
@@ -41,12 +62,28 @@ type percentileResponse struct {
 
 // statsResponse represents the JSON response for service statistics.
 type statsResponse struct {
-	// TotalEntries is the number of response time entries stored
+	// TotalEntries is the number of response time entries stored across
+	// every series.
 	TotalEntries int `json:"total_entries"`
-	// CacheValid indicates if the sorted cache is up to date
-	CacheValid bool `json:"cache_valid"`
-	// CacheSize is the size of the sorted cache
-	CacheSize int `json:"cache_size"`
+	// SeriesCount is the number of distinct label combinations tracked.
+	SeriesCount int `json:"series_count"`
+	// WindowOccupiedSeconds and WindowCapacitySeconds describe how much of
+	// the windowed backend's ring buffer currently holds samples, summed
+	// across every series. Both are 0 when the window backend isn't in use.
+	WindowOccupiedSeconds int `json:"window_occupied_seconds"`
+	WindowCapacitySeconds int `json:"window_capacity_seconds"`
+}
+
+// seriesInfo describes one label set and how many samples it has recorded,
+// as returned by the /series endpoint.
+type seriesInfo struct {
+	Labels map[string]string `json:"labels"`
+	Count  int               `json:"count"`
+}
+
+// seriesResponse represents the JSON response for the /series endpoint.
+type seriesResponse struct {
+	Series []seriesInfo `json:"series"`
 }
 
 // storeHandler handles POST requests to store response time entries.
@@ -85,21 +122,186 @@ func storeHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid timestamp", http.StatusBadRequest)
 		return
 	}
+	if err := validateTimestamp(ts); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	dur := time.Duration(req.DurationMs) * time.Millisecond
-	if err := svc.StoreResponseTime(ts, dur); err != nil {
+	labels := response_time.NewLabelSet(req.Labels)
+	stored, err := reg.Store(labels, ts, dur)
+	if err != nil {
 		http.Error(w, "failed to store", http.StatusInternalServerError)
 		return
 	}
+	recordMetric(stored, dur)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(storeResponse{Status: "ok"})
 }
 
+// storeBatchRequest represents the JSON payload for POST /store/bulk: an
+// array of entries, each in the same shape as a single /store request.
+type storeBatchRequest []storeRequest
+
+// storeStreamResponse represents the JSON response for POST /store/stream.
+type storeStreamResponse struct {
+	// Status indicates the result of the stream
+	Status string `json:"status"`
+	// Stored is the number of entries successfully stored before EOF
+	Stored int `json:"stored"`
+}
+
+// storeBulkHandler handles POST requests that store many response time
+// entries in one call. Entries are grouped by label set and written with
+// Registry.StoreBatch, taking each matching series' write lock once
+// instead of once per entry.
+//
+// Example request:
+//
+//	POST /store/bulk
+//	Content-Type: application/json
+//	[
+//	  {"timestamp": "2024-01-15T10:30:00Z", "duration_ms": 150},
+//	  {"timestamp": "2024-01-15T10:30:01Z", "duration_ms": 200}
+//	]
+//
+// Example response:
+//
+//	{
+//	  "status": "ok"
+//	}
+func storeBulkHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs storeBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	type group struct {
+		labels  response_time.LabelSet
+		entries []response_time.ResponseTimeEntry
+	}
+	groups := make(map[string]*group)
+
+	for _, req := range reqs {
+		ts, err := time.Parse(time.RFC3339, req.Timestamp)
+		if err != nil {
+			http.Error(w, "invalid timestamp", http.StatusBadRequest)
+			return
+		}
+		if err := validateTimestamp(ts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		labels := response_time.NewLabelSet(req.Labels)
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels}
+			groups[key] = g
+		}
+
+		dur := time.Duration(req.DurationMs) * time.Millisecond
+		g.entries = append(g.entries, response_time.ResponseTimeEntry{Timestamp: ts, Duration: dur})
+	}
+
+	for _, g := range groups {
+		stored, err := reg.StoreBatch(g.labels, g.entries)
+		if err != nil {
+			http.Error(w, "failed to store", http.StatusInternalServerError)
+			return
+		}
+		for _, e := range g.entries {
+			recordMetric(stored, e.Duration)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(storeResponse{Status: "ok"})
+}
+
+// storeStreamHandler handles POST requests whose body is a sequence of
+// JSON entries, one after another (e.g. newline-delimited JSON), each in
+// the same shape as a single /store request. Entries are read and stored
+// one at a time until EOF, so the request body never needs to be buffered
+// in full.
+//
+// Example request:
+//
+//	POST /store/stream
+//	Content-Type: application/x-ndjson
+//	{"timestamp": "2024-01-15T10:30:00Z", "duration_ms": 150}
+//	{"timestamp": "2024-01-15T10:30:01Z", "duration_ms": 200}
+//
+// Example response:
+//
+//	{
+//	  "status": "ok",
+//	  "stored": 2
+//	}
+func storeStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	stored := 0
+	for {
+		var req storeRequest
+		if err := dec.Decode(&req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		ts, err := time.Parse(time.RFC3339, req.Timestamp)
+		if err != nil {
+			http.Error(w, "invalid timestamp", http.StatusBadRequest)
+			return
+		}
+		if err := validateTimestamp(ts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dur := time.Duration(req.DurationMs) * time.Millisecond
+		labels := response_time.NewLabelSet(req.Labels)
+		storedLabels, err := reg.Store(labels, ts, dur)
+		if err != nil {
+			http.Error(w, "failed to store", http.StatusInternalServerError)
+			return
+		}
+		recordMetric(storedLabels, dur)
+		stored++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(storeStreamResponse{Status: "ok", Stored: stored})
+}
+
 // percentileHandler handles GET requests to retrieve response time percentiles.
 // It expects a query parameter 'percentile' with a value between 0 and 100.
 // The function returns the response time at the specified percentile.
 //
+// Repeated label=key:value query parameters narrow the query to series
+// whose labels contain every pair given, and the query fails if more than
+// one series matches; with no label parameters every series matches, so it
+// succeeds only while a single series exists. An optional group_by=key returns the
+// percentile for every distinct value of key among the matching series,
+// instead of a single number. An optional window=<duration> (e.g. "5m")
+// restricts the query to that trailing window instead of the series'
+// entire history; it requires the windowed storage backend.
+//
 // Example request:
 //
 //	GET /percentile?percentile=90
@@ -111,6 +313,21 @@ func storeHandler(w http.ResponseWriter, r *http.Request) {
 //	  "response_time_ms": 200
 //	}
 //
+// Example grouped request:
+//
+//	GET /percentile?percentile=99&label=route:/checkout&group_by=tenant
+//
+// Example grouped response:
+//
+//	{
+//	  "acme": 180,
+//	  "globex": 240
+//	}
+//
+// Example windowed request:
+//
+//	GET /percentile?percentile=99&window=5m
+//
 // Common percentiles:
 //   - 50: median response time
 //   - 90: 90th percentile (90% of requests are faster)
@@ -133,12 +350,50 @@ func percentileHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dur, err := svc.GetResponseTime(p)
+	filter, err := parseLabelParams(r.URL.Query()["label"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		groups, err := reg.GroupBy(filter, groupBy, p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		out := make(map[string]int64, len(groups))
+		for value, dur := range groups {
+			out[value] = dur.Milliseconds()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+		return
+	}
+
+	var dur time.Duration
+	if windowParam := r.URL.Query().Get("window"); windowParam != "" {
+		window, err := time.ParseDuration(windowParam)
+		if err != nil {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+		dur, err = reg.PercentileWindow(filter, p, window)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	} else {
+		var err error
+		dur, err = reg.Percentile(filter, p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(percentileResponse{
 		Percentile:     p,
@@ -146,8 +401,85 @@ func percentileHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// maxFutureSkew bounds how far ahead of the server's clock a stored
+// timestamp may be; the HTTP store handlers reject anything later. The
+// window backend does not rely on it: a sample further ahead of its ring's
+// head than the ring's capacity goes to its overflow bucket without
+// advancing the ring. A generous bound accommodates real clock skew
+// between producers and this server.
+const maxFutureSkew = 24 * time.Hour
+
+// validateTimestamp rejects a parsed timestamp that is implausibly far in
+// the future.
+func validateTimestamp(ts time.Time) error {
+	if ts.After(time.Now().Add(maxFutureSkew)) {
+		return fmt.Errorf("timestamp %s is too far in the future", ts.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// parseLabelParams parses repeated label=key:value query parameters into a
+// LabelSet suitable for filtering or grouping a Registry query.
+func parseLabelParams(raw []string) (response_time.LabelSet, error) {
+	labels := make(map[string]string, len(raw))
+	for _, kv := range raw {
+		parts := strings.SplitN(kv, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid label parameter %q: expected key:value", kv)
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return response_time.NewLabelSet(labels), nil
+}
+
+// seriesDirName derives a stable, filesystem-safe directory name from a
+// series' label set, so a restart maps each series back to the same
+// persistence directory regardless of the order label combinations arrive
+// in (arrival order is not reproducible across restarts, but the label
+// set's identity is).
+func seriesDirName(labels response_time.LabelSet) string {
+	h := fnv.New64a()
+	h.Write([]byte(labels.String()))
+	return fmt.Sprintf("series-%016x", h.Sum64())
+}
+
+// seriesHandler handles GET requests that list every known label
+// combination and how many samples it has recorded.
+//
+// Example request:
+//
+//	GET /series
+//
+// Example response:
+//
+//	{
+//	  "series": [
+//	    {"labels": {"route": "/checkout", "tenant": "acme"}, "count": 42}
+//	  ]
+//	}
+func seriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	list := reg.List()
+	out := make([]seriesInfo, len(list))
+	for i, s := range list {
+		labels := make(map[string]string, len(s.Labels))
+		for _, l := range s.Labels {
+			labels[l.Key] = l.Value
+		}
+		out[i] = seriesInfo{Labels: labels, Count: s.Count}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(seriesResponse{Series: out})
+}
+
 // statsHandler handles GET requests to retrieve service statistics.
-// It returns information about the stored data and cache status.
+// It returns the total number of entries stored across every series and
+// how many distinct label combinations are being tracked.
 //
 // Example request:
 //
@@ -157,8 +489,9 @@ func percentileHandler(w http.ResponseWriter, r *http.Request) {
 //
 //	{
 //	  "total_entries": 100,
-//	  "cache_valid": true,
-//	  "cache_size": 100
+//	  "series_count": 3,
+//	  "window_occupied_seconds": 0,
+//	  "window_capacity_seconds": 0
 //	}
 func statsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -166,16 +499,62 @@ func statsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats := svc.GetStats()
+	list := reg.List()
+	total, occupied, capacity := 0, 0, 0
+	for _, s := range list {
+		total += s.Count
+		occupied += s.WindowOccupied
+		capacity += s.WindowCapacity
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(statsResponse{
-		TotalEntries: stats["total_entries"].(int),
-		CacheValid:   stats["cache_valid"].(bool),
-		CacheSize:    stats["cache_size"].(int),
+		TotalEntries:          total,
+		SeriesCount:           len(list),
+		WindowOccupiedSeconds: occupied,
+		WindowCapacitySeconds: capacity,
 	})
 }
 
+// adminSnapshotResponse represents the JSON response for POST /admin/snapshot.
+type adminSnapshotResponse struct {
+	// Status indicates the result of the snapshot request.
+	Status string `json:"status"`
+	// Snapshotted is the number of series whose backend took a snapshot.
+	// Series whose backend doesn't support persistence are skipped.
+	Snapshotted int `json:"snapshotted"`
+}
+
+// adminSnapshotHandler handles POST requests that force every series with a
+// persistence-enabled backend to snapshot immediately, instead of waiting
+// for -persist-snapshot-entries to be reached.
+//
+// Example request:
+//
+//	POST /admin/snapshot
+//
+// Example response:
+//
+//	{
+//	  "status": "ok",
+//	  "snapshotted": 3
+//	}
+func adminSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	n, err := reg.Snapshot()
+	if err != nil {
+		http.Error(w, "failed to snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(adminSnapshotResponse{Status: "ok", Snapshotted: n})
+}
+
 // healthHandler handles GET requests for health checks.
 // It returns a simple health status for load balancers and monitoring.
 //
@@ -198,28 +577,144 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-// main starts the HTTP server and sets up the API endpoints.
-// The server listens on port 8080 and provides these endpoints:
-//   - POST /store: Store a response time entry
+// main starts the HTTP server and sets up the API endpoints. It also
+// starts a gRPC server on a second port exposing the same Store and
+// Percentile operations, defined in proto/response_time.proto.
+// The HTTP server listens on port 8080 and provides these endpoints:
+//   - POST /store: Store a response time entry, optionally labeled
+//   - POST /store/bulk: Store many response time entries in one request
+//   - POST /store/stream: Store a newline-delimited stream of entries
 //   - GET /percentile: Retrieve response time at a given percentile
+//   - GET /series: List known label combinations and their sample counts
 //   - GET /stats: Get service statistics
 //   - GET /health: Health check endpoint
+//   - GET /metrics: Prometheus metrics (only when built with -tags prometheus)
+//   - POST /admin/snapshot: Force every persistence-enabled series to snapshot
+//
+// The server uses the global registry instance to handle all operations.
+// All data is stored in memory and will be lost when the server restarts,
+// unless -persist-dir is set (slice backend only), in which case each
+// series replays its write-ahead log and latest snapshot on startup and the
+// server closes it cleanly on SIGINT/SIGTERM.
 //
-// The server uses the global service instance to handle all operations.
-// All data is stored in memory and will be lost when the server restarts.
+// The storage backend is selected with -backend:
+//   - slice (default): full-history slice with a sorted cache, exact
+//     percentiles, memory grows with the number of samples stored.
+//   - histogram: fixed-size HDR histogram, bounded memory, percentiles
+//     accurate to -histogram-digits significant digits.
+//   - window: ring buffer of per-second histograms, supporting trailing
+//     window queries via /percentile?window=5m in addition to the
+//     backend's full -window-seconds of retained history.
 func main() {
-	http.HandleFunc("/store", storeHandler)
-	http.HandleFunc("/percentile", percentileHandler)
-	http.HandleFunc("/stats", statsHandler)
-	http.HandleFunc("/health", healthHandler)
-
-	log.Println("üöÄ In-Memory Response Time Service starting...")
-	log.Println("üìä Endpoints available:")
-	log.Println("   POST /store      - Store response time")
-	log.Println("   GET  /percentile - Get percentile")
-	log.Println("   GET  /stats      - Service statistics")
-	log.Println("   GET  /health     - Health check")
-	log.Println("üåê Listening on :8080...")
+	backend := flag.String("backend", "slice", "storage backend to use: slice, histogram, or window")
+	histMin := flag.Duration("histogram-min", time.Microsecond, "smallest trackable duration (histogram and window backends)")
+	histMax := flag.Duration("histogram-max", 10*time.Minute, "largest trackable duration (histogram and window backends)")
+	histDigits := flag.Int("histogram-digits", 3, "significant decimal digits of precision, 1-5 (histogram and window backends)")
+	windowSeconds := flag.Int("window-seconds", 900, "seconds of per-second history to retain (window backend only)")
+	maxSeries := flag.Int("max-series", 1000, "maximum number of distinct label combinations tracked; additional combinations share an overflow series")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address the gRPC server listens on")
+	persistDir := flag.String("persist-dir", "", "directory for write-ahead-log and snapshot persistence (slice backend only); each series gets its own subdirectory. Empty disables persistence")
+	persistFlushEvery := flag.Int("persist-flush-every", 1, "flush the write-ahead log to disk every N stored entries (slice backend only, requires -persist-dir)")
+	persistSnapshotEntries := flag.Int("persist-snapshot-entries", 10000, "entries accumulated before an automatic snapshot is taken and the write-ahead log truncated (slice backend only, requires -persist-dir)")
+	flag.Parse()
+
+	newHist := func() *response_time.HistogramService {
+		hs, err := response_time.NewHistogramService(*histMin, *histMax, *histDigits)
+		if err != nil {
+			log.Fatalf("failed to create histogram service: %v", err)
+		}
+		return hs
+	}
+
+	var newSvc func(response_time.LabelSet) response_time.ResponseTimeService
+	switch *backend {
+	case "histogram":
+		newSvc = func(response_time.LabelSet) response_time.ResponseTimeService { return newHist() }
+	case "window":
+		newSvc = func(response_time.LabelSet) response_time.ResponseTimeService {
+			ws, err := response_time.NewWindowService(*windowSeconds, newHist)
+			if err != nil {
+				log.Fatalf("failed to create window service: %v", err)
+			}
+			return ws
+		}
+	case "slice":
+		newSvc = func(labels response_time.LabelSet) response_time.ResponseTimeService {
+			var opts []response_time.Option
+			if *persistDir != "" {
+				dir := filepath.Join(*persistDir, seriesDirName(labels))
+				opts = append(opts, response_time.WithPersistence(dir, *persistFlushEvery, *persistSnapshotEntries))
+			}
+			svc, err := response_time.NewService(opts...)
+			if err != nil {
+				log.Fatalf("failed to create service: %v", err)
+			}
+			return svc
+		}
+	default:
+		log.Fatalf("unknown backend %q: must be slice, histogram, or window", *backend)
+	}
+
+	reg = response_time.NewRegistry(*maxSeries, newSvc)
+
+	http.HandleFunc("/store", instrument("store", storeHandler))
+	http.HandleFunc("/store/bulk", instrument("store_bulk", storeBulkHandler))
+	http.HandleFunc("/store/stream", instrument("store_stream", storeStreamHandler))
+	http.HandleFunc("/percentile", instrument("percentile", percentileHandler))
+	http.HandleFunc("/series", instrument("series", seriesHandler))
+	http.HandleFunc("/stats", instrument("stats", statsHandler))
+	http.HandleFunc("/health", instrument("health", healthHandler))
+	http.HandleFunc("/admin/snapshot", instrument("admin_snapshot", adminSnapshotHandler))
+	registerMetricsEndpoint()
+
+	startGRPCServer(*grpcAddr)
+	go waitForShutdown()
+
+	log.Println("🚀 In-Memory Response Time Service starting...")
+	log.Println("📊 Endpoints available:")
+	log.Println("   POST /store          - Store response time")
+	log.Println("   POST /store/bulk     - Store many response times in one request")
+	log.Println("   POST /store/stream   - Store a newline-delimited stream of response times")
+	log.Println("   GET  /percentile     - Get percentile")
+	log.Println("   GET  /series         - List known label combinations")
+	log.Println("   GET  /stats          - Service statistics")
+	log.Println("   GET  /health         - Health check")
+	log.Println("   POST /admin/snapshot - Force a persistence snapshot")
+	log.Printf("🌐 Listening on :8080 (HTTP) and %s (gRPC)...\n", *grpcAddr)
 
 	log.Fatal(http.ListenAndServe(":8080", nil))
 }
+
+// waitForShutdown blocks until SIGINT or SIGTERM, then closes the registry
+// so any persistence-enabled series fsyncs and closes its write-ahead log
+// before the process exits.
+func waitForShutdown() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+
+	log.Println("shutting down, closing registry...")
+	if err := reg.Close(); err != nil {
+		log.Fatalf("failed to close registry cleanly: %v", err)
+	}
+	os.Exit(0)
+}
+
+// startGRPCServer starts the gRPC ResponseTimeService defined in
+// proto/response_time.proto on addr, backed by the same registry as the
+// HTTP API, running in the background for the lifetime of the process.
+func startGRPCServer(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s for gRPC: %v", addr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	grpcserver.New(reg).Register(grpcServer)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/anandpulakala/InMemoryService/internal/response_time"
+)
+
+// TestSeriesDirNameStableAcrossOrder is a regression test for a restart bug:
+// series directories used to be assigned by an incrementing counter in
+// first-seen order, so a series replayed a different label set's data after
+// a restart whenever label combinations arrived in a different order.
+// seriesDirName must depend only on the label set's own identity.
+func TestSeriesDirNameStableAcrossOrder(t *testing.T) {
+	checkout := response_time.NewLabelSet(map[string]string{"route": "/checkout"})
+	login := response_time.NewLabelSet(map[string]string{"route": "/login"})
+
+	// Run 1: checkout seen first.
+	checkoutDir1 := seriesDirName(checkout)
+	loginDir1 := seriesDirName(login)
+
+	// Run 2 (e.g. after a restart): login seen first.
+	loginDir2 := seriesDirName(login)
+	checkoutDir2 := seriesDirName(checkout)
+
+	if checkoutDir1 != checkoutDir2 {
+		t.Errorf("checkout dir changed across runs: %q vs %q", checkoutDir1, checkoutDir2)
+	}
+	if loginDir1 != loginDir2 {
+		t.Errorf("login dir changed across runs: %q vs %q", loginDir1, loginDir2)
+	}
+	if checkoutDir1 == loginDir1 {
+		t.Errorf("checkout and login hashed to the same directory %q", checkoutDir1)
+	}
+}
+
+func TestSeriesDirNameDistinctLabels(t *testing.T) {
+	empty := response_time.NewLabelSet(nil)
+	route := response_time.NewLabelSet(map[string]string{"route": "/checkout"})
+
+	if seriesDirName(empty) == seriesDirName(route) {
+		t.Error("empty label set and a real one hashed to the same directory")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build prometheus
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/anandpulakala/InMemoryService/internal/response_time"
+)
+
+// Metrics exported when the binary is built with -tags prometheus. The
+// response_time_* families are fed by the HTTP store handlers (/store,
+// /store/bulk and /store/stream); samples stored through the gRPC server
+// go into the registry but are not recorded in these metrics.
+var (
+	responseTimeSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "response_time_seconds",
+		Help:    "Response times recorded via /store, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"series"})
+
+	responseTimeSecondsSummary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "response_time_seconds_summary",
+		Help:       "Response times recorded via /store, in seconds.",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	}, []string{"series"})
+
+	responseTimeEntriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "response_time_entries_total",
+		Help: "Total number of response time entries recorded via /store.",
+	}, []string{"series"})
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of requests handled by this server's own HTTP handlers.",
+	}, []string{"handler", "method"})
+
+	httpRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of this server's own HTTP handlers, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler", "method"})
+
+	httpRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of in-flight requests per handler on this server.",
+	}, []string{"handler"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		responseTimeSeconds,
+		responseTimeSecondsSummary,
+		responseTimeEntriesTotal,
+		httpRequestsTotal,
+		httpRequestDurationSeconds,
+		httpRequestsInFlight,
+	)
+}
+
+// recordMetric feeds a stored sample into the Prometheus histogram,
+// summary, and counter, tagged by its series' canonical label string.
+func recordMetric(labels response_time.LabelSet, duration time.Duration) {
+	series := labels.String()
+	seconds := duration.Seconds()
+
+	responseTimeSeconds.WithLabelValues(series).Observe(seconds)
+	responseTimeSecondsSummary.WithLabelValues(series).Observe(seconds)
+	responseTimeEntriesTotal.WithLabelValues(series).Inc()
+}
+
+// instrument wraps an HTTP handler with request-count, duration, and
+// in-flight instrumentation, labeled by handler name and method, so
+// operators can monitor this server the same way it monitors upstream
+// response times.
+func instrument(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httpRequestsInFlight.WithLabelValues(name).Inc()
+		defer httpRequestsInFlight.WithLabelValues(name).Dec()
+
+		start := time.Now()
+		next(w, r)
+
+		httpRequestsTotal.WithLabelValues(name, r.Method).Inc()
+		httpRequestDurationSeconds.WithLabelValues(name, r.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// registerMetricsEndpoint exposes the Prometheus metrics above on /metrics.
+func registerMetricsEndpoint() {
+	http.Handle("/metrics", promhttp.Handler())
+}
@@ -0,0 +1,21 @@
+//go:build !prometheus
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anandpulakala/InMemoryService/internal/response_time"
+)
+
+// recordMetric is a no-op in the default build. Build with -tags
+// prometheus to export these as Prometheus metrics instead.
+func recordMetric(labels response_time.LabelSet, duration time.Duration) {}
+
+// instrument passes requests through unmodified in the default build.
+func instrument(name string, next http.HandlerFunc) http.HandlerFunc { return next }
+
+// registerMetricsEndpoint is a no-op in the default build; /metrics is only
+// served when built with -tags prometheus.
+func registerMetricsEndpoint() {}
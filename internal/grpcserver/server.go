@@ -0,0 +1,95 @@
+// Package grpcserver implements the gRPC ResponseTimeService defined in
+// proto/response_time.proto, backed by the same response_time.Registry
+// that serves the JSON HTTP API, so both protocols see the same data.
+//
+// The generated client/server stubs (response_timepb) are checked into
+// proto/ and produced from proto/response_time.proto by `make proto`; this
+// file only needs to change when the .proto's RPCs change.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/anandpulakala/InMemoryService/internal/response_time"
+	pb "github.com/anandpulakala/InMemoryService/proto"
+)
+
+// Server implements pb.ResponseTimeServiceServer on top of a Registry.
+type Server struct {
+	pb.UnimplementedResponseTimeServiceServer
+
+	reg *response_time.Registry
+}
+
+// New returns a gRPC server backed by reg.
+func New(reg *response_time.Registry) *Server {
+	return &Server{reg: reg}
+}
+
+// Register registers s as the ResponseTimeService implementation on
+// grpcServer.
+func (s *Server) Register(grpcServer *grpc.Server) {
+	pb.RegisterResponseTimeServiceServer(grpcServer, s)
+}
+
+// Store implements the unary Store RPC.
+func (s *Server) Store(ctx context.Context, req *pb.StoreRequest) (*pb.StoreResponse, error) {
+	if req.GetEntry() == nil {
+		return nil, errors.New("entry is required")
+	}
+	if err := s.store(req.GetEntry()); err != nil {
+		return nil, err
+	}
+	return &pb.StoreResponse{Ok: true}, nil
+}
+
+// StoreStream implements the client-streaming StoreStream RPC: it stores
+// every entry the client sends until the stream closes, then responds
+// once.
+func (s *Server) StoreStream(stream pb.ResponseTimeService_StoreStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return stream.SendAndClose(&pb.StoreResponse{Ok: true})
+			}
+			return err
+		}
+		if err := s.store(req.GetEntry()); err != nil {
+			return err
+		}
+	}
+}
+
+// Percentile implements the unary Percentile RPC.
+func (s *Server) Percentile(ctx context.Context, req *pb.PercentileRequest) (*pb.PercentileResponse, error) {
+	labels := response_time.NewLabelSet(req.GetLabels())
+
+	var (
+		dur time.Duration
+		err error
+	)
+	if ws := req.GetWindowSeconds(); ws > 0 {
+		dur, err = s.reg.PercentileWindow(labels, req.GetPercentile(), time.Duration(ws)*time.Second)
+	} else {
+		dur, err = s.reg.Percentile(labels, req.GetPercentile())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PercentileResponse{ResponseTimeMs: dur.Milliseconds()}, nil
+}
+
+func (s *Server) store(entry *pb.ResponseTimeEntry) error {
+	ts := entry.GetTimestamp().AsTime()
+	dur := time.Duration(entry.GetDurationMs()) * time.Millisecond
+	labels := response_time.NewLabelSet(entry.GetLabels())
+	_, err := s.reg.Store(labels, ts, dur)
+	return err
+}
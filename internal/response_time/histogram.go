@@ -0,0 +1,248 @@
+package response_time
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// HistogramService is an alternative to Service that records response time
+// samples into a fixed-size HDR (High Dynamic Range) histogram instead of a
+// growing slice. Memory usage is bounded by the configured value range and
+// precision rather than by the number of samples recorded, and both
+// StoreResponseTime and GetResponseTime run in time independent of the
+// number of samples stored: O(1) for a store, O(buckets) for a percentile
+// query.
+//
+// Values are grouped into power-of-two "buckets"; each bucket is split into
+// a fixed number of linear "sub-buckets" so that every recorded value keeps
+// at least significantDigits decimal digits of precision, following the
+// scheme described at http://hdrhistogram.org.
+type HistogramService struct {
+	mu sync.RWMutex
+
+	min, max          time.Duration
+	significantDigits int
+
+	unitMagnitude               uint
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketHalfCountMagnitude uint
+	subBucketMask               int64
+
+	bucketCount int
+	counts      []int64
+	totalCount  int64
+}
+
+// subBucketsForDigits returns the number of sub-buckets per power-of-two
+// bucket required to preserve the requested number of significant decimal
+// digits of precision.
+func subBucketsForDigits(significantDigits int) int {
+	switch {
+	case significantDigits <= 2:
+		return 128
+	case significantDigits == 3:
+		return 1024
+	case significantDigits == 4:
+		return 2048
+	default:
+		return 4096
+	}
+}
+
+// NewHistogramService creates a Service-compatible backend that records
+// durations into a fixed-size HDR histogram covering [min, max] with the
+// requested number of significant decimal digits (1-5). Unlike NewService,
+// memory usage does not grow with the number of samples recorded.
+func NewHistogramService(min, max time.Duration, significantDigits int) (*HistogramService, error) {
+	if min <= 0 {
+		return nil, errors.New("min must be positive")
+	}
+	if max <= min {
+		return nil, errors.New("max must be greater than min")
+	}
+	if significantDigits < 1 || significantDigits > 5 {
+		return nil, errors.New("significantDigits must be between 1 and 5")
+	}
+
+	h := &HistogramService{
+		min:               min,
+		max:               max,
+		significantDigits: significantDigits,
+	}
+
+	h.subBucketCount = subBucketsForDigits(significantDigits)
+	h.subBucketHalfCount = h.subBucketCount / 2
+	h.subBucketHalfCountMagnitude = uint(bits.Len(uint(h.subBucketCount))) - 2
+	h.unitMagnitude = uint(bits.Len64(uint64(min))) - 1
+	h.subBucketMask = int64(h.subBucketCount-1) << h.unitMagnitude
+
+	smallestUntrackableValue := int64(h.subBucketCount) << h.unitMagnitude
+	bucketsNeeded := 1
+	for smallestUntrackableValue < int64(max) {
+		smallestUntrackableValue <<= 1
+		bucketsNeeded++
+	}
+	h.bucketCount = bucketsNeeded
+
+	countsLen := (h.bucketCount + 1) * h.subBucketHalfCount
+	h.counts = make([]int64, countsLen)
+
+	return h, nil
+}
+
+// bucketIndex returns the power-of-two bucket a value falls into.
+func (h *HistogramService) bucketIndex(value int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(value)|uint64(h.subBucketMask))
+	return pow2Ceiling - int(h.unitMagnitude) - int(h.subBucketHalfCountMagnitude) - 1
+}
+
+// subBucketIndex returns the linear sub-bucket index of value within the
+// power-of-two bucket bucketIdx.
+func (h *HistogramService) subBucketIndex(value int64, bucketIdx int) int {
+	return int(value >> (uint(bucketIdx) + h.unitMagnitude))
+}
+
+// countsIndex maps a (bucketIdx, subBucketIdx) pair to a slot in counts.
+func (h *HistogramService) countsIndex(bucketIdx, subBucketIdx int) int {
+	bucketBaseIdx := (bucketIdx + 1) << h.subBucketHalfCountMagnitude
+	offsetInBucket := subBucketIdx - h.subBucketHalfCount
+	return bucketBaseIdx + offsetInBucket
+}
+
+// indexFor returns the counts slot that value should be recorded into. It
+// assumes value has already been clamped to [min, max].
+func (h *HistogramService) indexFor(value int64) int {
+	bucketIdx := h.bucketIndex(value)
+	if bucketIdx >= h.bucketCount {
+		return len(h.counts) - 1
+	}
+	if bucketIdx < 0 {
+		bucketIdx = 0
+	}
+	subBucketIdx := h.subBucketIndex(value, bucketIdx)
+	if subBucketIdx >= h.subBucketCount {
+		bucketIdx++
+		subBucketIdx = h.subBucketIndex(value, bucketIdx)
+	}
+	return h.countsIndex(bucketIdx, subBucketIdx)
+}
+
+// valueFromIndex reconstructs the upper bound of the bucket represented by
+// a counts slot.
+func (h *HistogramService) valueFromIndex(idx int) int64 {
+	bucketIdx := (idx >> h.subBucketHalfCountMagnitude) - 1
+	subBucketIdx := (idx & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+	if bucketIdx < 0 {
+		subBucketIdx -= h.subBucketHalfCount
+		bucketIdx = 0
+	}
+
+	shift := uint(bucketIdx) + h.unitMagnitude
+	lowest := int64(subBucketIdx) << shift
+	rangeSize := int64(1) << shift
+	return lowest + rangeSize - 1
+}
+
+// StoreResponseTime records a duration in O(1) time by incrementing the
+// counter for the bucket it falls into. Values outside [min, max] are
+// clamped to the nearest edge bucket rather than rejected, so the histogram
+// never grows and a query never fails because of an out-of-range sample.
+func (h *HistogramService) StoreResponseTime(_ time.Time, duration time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	v := int64(duration)
+	if v < int64(h.min) {
+		v = int64(h.min)
+	}
+	if v > int64(h.max) {
+		v = int64(h.max)
+	}
+
+	h.counts[h.indexFor(v)]++
+	h.totalCount++
+
+	return nil
+}
+
+// StoreBatch records every entry in one call, taking the write lock once
+// instead of once per entry.
+func (h *HistogramService) StoreBatch(entries []ResponseTimeEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, e := range entries {
+		v := int64(e.Duration)
+		if v < int64(h.min) {
+			v = int64(h.min)
+		}
+		if v > int64(h.max) {
+			v = int64(h.max)
+		}
+		h.counts[h.indexFor(v)]++
+		h.totalCount++
+	}
+
+	return nil
+}
+
+// GetResponseTime returns the response time at the given percentile in
+// O(buckets) time, independent of how many samples have been recorded. The
+// returned value is the upper bound of the bucket the percentile falls
+// into, so it is accurate to the configured number of significant digits
+// rather than exact.
+func (h *HistogramService) GetResponseTime(percentile float64) (time.Duration, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.totalCount == 0 {
+		return 0, errors.New("no data available")
+	}
+	if percentile < 0 || percentile > 100 {
+		return 0, errors.New("percentile must be between 0 and 100")
+	}
+
+	target := int64(percentile / 100.0 * float64(h.totalCount))
+	if target < 1 {
+		target = 1
+	}
+
+	var cumulative int64
+	for idx, c := range h.counts {
+		cumulative += c
+		if cumulative >= target {
+			return time.Duration(h.valueFromIndex(idx)), nil
+		}
+	}
+
+	return h.max, nil
+}
+
+// snapshot returns a copy of the bucket-counter array plus the function
+// needed to turn an index back into a duration, for merging with other
+// histogram series in percentileFromSnapshots.
+func (h *HistogramService) snapshot() sampleSnapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	counts := make([]int64, len(h.counts))
+	copy(counts, h.counts)
+	return sampleSnapshot{counts: counts, valueFromIdx: h.valueFromIndex}
+}
+
+// GetStats returns basic statistics about the histogram, mirroring the
+// shape returned by Service.GetStats so callers can treat both backends
+// interchangeably.
+func (h *HistogramService) GetStats() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return map[string]interface{}{
+		"total_entries": int(h.totalCount),
+		"cache_valid":   true,
+		"cache_size":    len(h.counts),
+	}
+}
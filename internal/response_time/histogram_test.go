@@ -0,0 +1,64 @@
+package response_time
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramServiceStoreAndPercentile(t *testing.T) {
+	h, err := NewHistogramService(time.Microsecond, 10*time.Second, 3)
+	if err != nil {
+		t.Fatalf("NewHistogramService: %v", err)
+	}
+
+	for _, ms := range []int64{100, 200, 300, 400, 500} {
+		if err := h.StoreResponseTime(time.Now(), time.Duration(ms)*time.Millisecond); err != nil {
+			t.Fatalf("StoreResponseTime: %v", err)
+		}
+	}
+
+	p50, err := h.GetResponseTime(50)
+	if err != nil {
+		t.Fatalf("GetResponseTime: %v", err)
+	}
+
+	// Nearest-rank over 5 samples puts the 50th percentile at the 2nd
+	// smallest value (200ms). The histogram only guarantees 3 significant
+	// digits of precision, so allow a small margin instead of requiring the
+	// exact stored value.
+	want := 200 * time.Millisecond
+	if diff := p50 - want; diff < -2*time.Millisecond || diff > 2*time.Millisecond {
+		t.Errorf("p50 = %v, want approximately %v", p50, want)
+	}
+}
+
+func TestHistogramServiceClampsOutOfRangeValues(t *testing.T) {
+	h, err := NewHistogramService(time.Millisecond, time.Second, 3)
+	if err != nil {
+		t.Fatalf("NewHistogramService: %v", err)
+	}
+
+	if err := h.StoreResponseTime(time.Now(), time.Hour); err != nil {
+		t.Fatalf("StoreResponseTime: %v", err)
+	}
+
+	p99, err := h.GetResponseTime(99)
+	if err != nil {
+		t.Fatalf("GetResponseTime: %v", err)
+	}
+	if p99 > time.Second+time.Millisecond {
+		t.Errorf("p99 = %v, want clamped to roughly max (1s)", p99)
+	}
+}
+
+func TestNewHistogramServiceRejectsInvalidRange(t *testing.T) {
+	if _, err := NewHistogramService(0, time.Second, 3); err == nil {
+		t.Error("min=0: want error, got nil")
+	}
+	if _, err := NewHistogramService(time.Second, time.Millisecond, 3); err == nil {
+		t.Error("max<min: want error, got nil")
+	}
+	if _, err := NewHistogramService(time.Millisecond, time.Second, 6); err == nil {
+		t.Error("significantDigits=6: want error, got nil")
+	}
+}
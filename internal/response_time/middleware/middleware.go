@@ -0,0 +1,123 @@
+// Package middleware provides http.Handler wrappers that automatically
+// record every wrapped endpoint's latency into a response_time.Registry,
+// without the caller ever touching the store HTTP API or gRPC service
+// directly.
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anandpulakala/InMemoryService/internal/response_time"
+)
+
+// Recorder wraps HTTP handlers to record their latency into a Registry.
+// Samples are buffered per label set and flushed with Registry.StoreBatch
+// on a timer, rather than taking the registry's write lock on every single
+// request.
+//
+// Recorder is safe for concurrent use.
+type Recorder struct {
+	reg        *response_time.Registry
+	baseLabels map[string]string
+	flushEvery time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingSeries
+
+	stop chan struct{}
+}
+
+// pendingSeries accumulates samples for one label set between flushes.
+type pendingSeries struct {
+	labels  response_time.LabelSet
+	entries []response_time.ResponseTimeEntry
+}
+
+// NewRecorder returns a Recorder that records every wrapped handler's
+// latency into reg, tagged with baseLabels plus a "route" and "method"
+// label per request, flushing buffered samples at least every flushEvery.
+// Call Close when done to stop the background flush loop and flush
+// anything still buffered.
+func NewRecorder(reg *response_time.Registry, baseLabels map[string]string, flushEvery time.Duration) *Recorder {
+	rc := &Recorder{
+		reg:        reg,
+		baseLabels: baseLabels,
+		flushEvery: flushEvery,
+		pending:    make(map[string]*pendingSeries),
+		stop:       make(chan struct{}),
+	}
+	go rc.flushLoop()
+	return rc
+}
+
+// Wrap returns an http.Handler that serves requests with next and then
+// buffers the request's latency under route/method labels merged with the
+// Recorder's base labels, for the next flush.
+func (rc *Recorder) Wrap(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		rc.record(route, r.Method, start, time.Since(start))
+	})
+}
+
+func (rc *Recorder) record(route, method string, ts time.Time, duration time.Duration) {
+	labels := make(map[string]string, len(rc.baseLabels)+2)
+	for k, v := range rc.baseLabels {
+		labels[k] = v
+	}
+	labels["route"] = route
+	labels["method"] = method
+
+	ls := response_time.NewLabelSet(labels)
+	key := ls.String()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	p, ok := rc.pending[key]
+	if !ok {
+		p = &pendingSeries{labels: ls}
+		rc.pending[key] = p
+	}
+	p.entries = append(p.entries, response_time.ResponseTimeEntry{Timestamp: ts, Duration: duration})
+}
+
+// Flush stores every currently buffered sample via Registry.StoreBatch,
+// one call per distinct label set, and clears the buffer.
+func (rc *Recorder) Flush() {
+	rc.mu.Lock()
+	pending := rc.pending
+	rc.pending = make(map[string]*pendingSeries)
+	rc.mu.Unlock()
+
+	for _, p := range pending {
+		if len(p.entries) == 0 {
+			continue
+		}
+		_, _ = rc.reg.StoreBatch(p.labels, p.entries)
+	}
+}
+
+func (rc *Recorder) flushLoop() {
+	ticker := time.NewTicker(rc.flushEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rc.Flush()
+		case <-rc.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background flush loop and flushes any samples still
+// buffered.
+func (rc *Recorder) Close() {
+	close(rc.stop)
+	rc.Flush()
+}
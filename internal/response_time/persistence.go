@@ -0,0 +1,191 @@
+package response_time
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// walRecordSize is the on-disk size of one WAL record: an int64 Unix nano
+// timestamp followed by an int64 nanosecond duration.
+const walRecordSize = 16
+
+// persistence gives a Service write-ahead-log plus periodic snapshot
+// durability: every stored entry is appended to wal.log (flushed to disk
+// every flushEvery entries), and the full dataset is periodically written
+// to snapshot.bin so that replaying after a restart only has to scan the
+// WAL entries appended since the last snapshot.
+type persistence struct {
+	mu sync.Mutex
+
+	dir       string
+	walFile   *os.File
+	walWriter *bufio.Writer
+
+	flushEvery      int
+	unflushed       int
+	snapshotEntries int
+	sinceSnapshot   int
+}
+
+// newPersistence opens (creating if necessary) dir/wal.log for appending.
+// Callers must call replay before serving requests, to recover any
+// previously durable state.
+func newPersistence(dir string, flushEvery, snapshotEntries int) (*persistence, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "wal.log"), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if flushEvery <= 0 {
+		flushEvery = 1
+	}
+	if snapshotEntries <= 0 {
+		snapshotEntries = 10000
+	}
+
+	return &persistence{
+		dir:             dir,
+		walFile:         f,
+		walWriter:       bufio.NewWriter(f),
+		flushEvery:      flushEvery,
+		snapshotEntries: snapshotEntries,
+	}, nil
+}
+
+func (p *persistence) snapshotPath() string { return filepath.Join(p.dir, "snapshot.bin") }
+func (p *persistence) walPath() string      { return filepath.Join(p.dir, "wal.log") }
+
+// append writes one record to the WAL, flushing to disk every flushEvery
+// records so a crash loses at most flushEvery-1 unflushed samples.
+func (p *persistence) append(entry ResponseTimeEntry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf [walRecordSize]byte
+	binary.LittleEndian.PutUint64(buf[0:8], uint64(entry.Timestamp.UnixNano()))
+	binary.LittleEndian.PutUint64(buf[8:16], uint64(entry.Duration))
+
+	if _, err := p.walWriter.Write(buf[:]); err != nil {
+		return err
+	}
+
+	p.unflushed++
+	p.sinceSnapshot++
+	if p.unflushed < p.flushEvery {
+		return nil
+	}
+
+	p.unflushed = 0
+	return p.walWriter.Flush()
+}
+
+// shouldSnapshot reports whether enough entries have accumulated since the
+// last snapshot to warrant taking a new one.
+func (p *persistence) shouldSnapshot() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sinceSnapshot >= p.snapshotEntries
+}
+
+// snapshot atomically replaces snapshot.bin with durations (one
+// little-endian int64 nanosecond duration per entry) via write-then-rename,
+// then truncates the WAL, since every entry it held is now captured in the
+// snapshot.
+func (p *persistence) snapshot(durations []int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tmp := p.snapshotPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 8*len(durations))
+	for i, d := range durations {
+		binary.LittleEndian.PutUint64(buf[i*8:i*8+8], uint64(d))
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p.snapshotPath()); err != nil {
+		return err
+	}
+
+	if err := p.walWriter.Flush(); err != nil {
+		return err
+	}
+	if err := p.walFile.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := p.walFile.Seek(0, 0); err != nil {
+		return err
+	}
+
+	p.sinceSnapshot = 0
+	p.unflushed = 0
+	return nil
+}
+
+// replay restores entries from snapshot.bin (if one exists) followed by
+// wal.log. A WAL tail shorter than one full record is treated as a torn
+// write from a crash mid-append and simply ends replay there, rather than
+// failing it.
+func (p *persistence) replay() ([]ResponseTimeEntry, error) {
+	var entries []ResponseTimeEntry
+
+	snapshotData, err := os.ReadFile(p.snapshotPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for i := 0; i+8 <= len(snapshotData); i += 8 {
+		d := int64(binary.LittleEndian.Uint64(snapshotData[i : i+8]))
+		entries = append(entries, ResponseTimeEntry{Duration: time.Duration(d)})
+	}
+
+	walData, err := os.ReadFile(p.walPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for i := 0; i+walRecordSize <= len(walData); i += walRecordSize {
+		nanos := int64(binary.LittleEndian.Uint64(walData[i : i+8]))
+		dur := int64(binary.LittleEndian.Uint64(walData[i+8 : i+16]))
+		entries = append(entries, ResponseTimeEntry{
+			Timestamp: time.Unix(0, nanos),
+			Duration:  time.Duration(dur),
+		})
+	}
+
+	return entries, nil
+}
+
+// Close flushes and fsyncs the WAL, then closes it.
+func (p *persistence) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.walWriter.Flush(); err != nil {
+		return err
+	}
+	if err := p.walFile.Sync(); err != nil {
+		return err
+	}
+	return p.walFile.Close()
+}
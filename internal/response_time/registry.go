@@ -0,0 +1,372 @@
+package response_time
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Label is a single key/value dimension attached to a stored sample, e.g.
+// {Key: "route", Value: "/checkout"} or {Key: "tenant", Value: "acme"}.
+type Label struct {
+	Key   string
+	Value string
+}
+
+// LabelSet is a canonical, sorted set of labels identifying one series
+// within a Registry. Sorting makes two maps with identical contents compare
+// equal regardless of iteration order.
+type LabelSet []Label
+
+// NewLabelSet builds a canonical LabelSet from a map, sorted by key.
+func NewLabelSet(labels map[string]string) LabelSet {
+	ls := make(LabelSet, 0, len(labels))
+	for k, v := range labels {
+		ls = append(ls, Label{Key: k, Value: v})
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Key < ls[j].Key })
+	return ls
+}
+
+// String returns labels in canonical "k1=v1,k2=v2" form. It is exported
+// for callers outside this package that want a stable tag for a label set,
+// such as the optional Prometheus integration's per-series metrics.
+func (ls LabelSet) String() string {
+	return ls.key()
+}
+
+// key returns a canonical string for ls, suitable for use as a map key.
+func (ls LabelSet) key() string {
+	var b strings.Builder
+	for i, l := range ls {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Key)
+		b.WriteByte('=')
+		b.WriteString(l.Value)
+	}
+	return b.String()
+}
+
+// matches reports whether ls contains every key/value pair in filter. An
+// empty filter matches everything.
+func (ls LabelSet) matches(filter LabelSet) bool {
+	for _, f := range filter {
+		found := false
+		for _, l := range ls {
+			if l.Key == f.Key && l.Value == f.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// value returns the value associated with key in ls, and whether it was
+// present at all.
+func (ls LabelSet) value(key string) (string, bool) {
+	for _, l := range ls {
+		if l.Key == key {
+			return l.Value, true
+		}
+	}
+	return "", false
+}
+
+// seriesFactory creates a new, empty backend for a series the first time a
+// Registry sees its label set. labels identifies the series being created
+// (the reserved overflowLabelSet for the shared overflow series), so a
+// factory that persists to disk can derive a stable directory name from
+// the label set's identity rather than from arrival order. All series in
+// a Registry must be created by the same factory so their snapshots
+// remain mergeable in GroupBy.
+type seriesFactory func(labels LabelSet) ResponseTimeService
+
+// overflowLabelSet identifies the Registry's shared overflow series passed
+// to seriesFactory. It uses a key no real label map can produce, since map
+// keys can't contain NUL bytes when round-tripped through JSON.
+var overflowLabelSet = LabelSet{{Key: "\x00overflow", Value: "\x00"}}
+
+// series is one label set's backend.
+type series struct {
+	labels LabelSet
+	svc    ResponseTimeService
+}
+
+// Registry fans a single response_time backend out into many independent
+// series, one per unique label set, so a server can answer queries like
+// "p99 of GET /checkout for tenant acme" instead of lumping every
+// measurement into one bag.
+//
+// Registry is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	bySeries  map[string]*series
+	newSvc    seriesFactory
+	maxSeries int
+	// overflow absorbs samples for label combinations seen after maxSeries
+	// distinct series already exist, so a cardinality blowup degrades to a
+	// single shared bucket instead of being silently dropped.
+	overflow ResponseTimeService
+}
+
+// NewRegistry creates a Registry that lazily creates a new backend (via
+// newSvc) for each unique label set it sees, up to maxSeries distinct
+// series. A maxSeries of 0 means unlimited. Once the limit is reached,
+// samples for new label combinations are folded into a shared overflow
+// series rather than rejected outright.
+func NewRegistry(maxSeries int, newSvc seriesFactory) *Registry {
+	return &Registry{
+		bySeries:  make(map[string]*series),
+		newSvc:    newSvc,
+		maxSeries: maxSeries,
+		overflow:  newSvc(overflowLabelSet),
+	}
+}
+
+// Store records a sample against the series identified by labels, creating
+// the series if this is the first sample seen for that exact label set. It
+// returns the label set the sample was actually recorded under, which is
+// overflowLabelSet rather than labels once maxSeries has been reached;
+// callers that derive anything (e.g. metrics) from "which series did this
+// land in" must use the returned label set, not their own input, or they
+// bypass the cardinality cap entirely.
+func (r *Registry) Store(labels LabelSet, ts time.Time, duration time.Duration) (LabelSet, error) {
+	r.mu.Lock()
+	s, ok := r.bySeries[labels.key()]
+	if !ok {
+		if r.maxSeries > 0 && len(r.bySeries) >= r.maxSeries {
+			r.mu.Unlock()
+			return overflowLabelSet, r.overflow.StoreResponseTime(ts, duration)
+		}
+		s = &series{labels: labels, svc: r.newSvc(labels)}
+		r.bySeries[labels.key()] = s
+	}
+	r.mu.Unlock()
+
+	return s.labels, s.svc.StoreResponseTime(ts, duration)
+}
+
+// StoreBatch records every entry against the series identified by labels
+// in one call, creating the series if needed. All of entries must already
+// share the same labels; callers ingesting a mix of label sets should
+// group entries by label set first and call StoreBatch once per group. It
+// returns the label set the entries were actually recorded under, which is
+// overflowLabelSet rather than labels once maxSeries has been reached; see
+// Store for why callers must use the returned label set.
+func (r *Registry) StoreBatch(labels LabelSet, entries []ResponseTimeEntry) (LabelSet, error) {
+	r.mu.Lock()
+	s, ok := r.bySeries[labels.key()]
+	if !ok {
+		if r.maxSeries > 0 && len(r.bySeries) >= r.maxSeries {
+			r.mu.Unlock()
+			return overflowLabelSet, r.overflow.StoreBatch(entries)
+		}
+		s = &series{labels: labels, svc: r.newSvc(labels)}
+		r.bySeries[labels.key()] = s
+	}
+	r.mu.Unlock()
+
+	return s.labels, s.svc.StoreBatch(entries)
+}
+
+// Percentile returns the response time at percentile for the series whose
+// labels match filter. filter need not be exhaustive: if exactly one known
+// series contains every label in filter, that series answers the query. An
+// empty filter only matches when a single series exists overall.
+func (r *Registry) Percentile(filter LabelSet, percentile float64) (time.Duration, error) {
+	r.mu.RLock()
+	var match *series
+	ambiguous := false
+	for _, s := range r.bySeries {
+		if !s.labels.matches(filter) {
+			continue
+		}
+		if match != nil {
+			ambiguous = true
+			break
+		}
+		match = s
+	}
+	r.mu.RUnlock()
+
+	if ambiguous {
+		return 0, errors.New("labels match more than one series; use group_by to aggregate them")
+	}
+	if match == nil {
+		return 0, errors.New("no data available")
+	}
+	return match.svc.GetResponseTime(percentile)
+}
+
+// windowedSeries is implemented by backends that support trailing-window
+// percentile queries (currently only WindowService).
+type windowedSeries interface {
+	GetResponseTimeWindow(percentile float64, window time.Duration) (time.Duration, error)
+}
+
+// PercentileWindow is like Percentile but answers over the trailing window
+// duration instead of a series' entire history. It returns an error if the
+// matched series' backend does not support windowed queries.
+func (r *Registry) PercentileWindow(filter LabelSet, percentile float64, window time.Duration) (time.Duration, error) {
+	r.mu.RLock()
+	var match *series
+	ambiguous := false
+	for _, s := range r.bySeries {
+		if !s.labels.matches(filter) {
+			continue
+		}
+		if match != nil {
+			ambiguous = true
+			break
+		}
+		match = s
+	}
+	r.mu.RUnlock()
+
+	if ambiguous {
+		return 0, errors.New("labels match more than one series; use group_by to aggregate them")
+	}
+	if match == nil {
+		return 0, errors.New("no data available")
+	}
+
+	ws, ok := match.svc.(windowedSeries)
+	if !ok {
+		return 0, errors.New("backend does not support windowed queries")
+	}
+	return ws.GetResponseTimeWindow(percentile, window)
+}
+
+// GroupBy returns, for every distinct value of groupKey among series whose
+// labels match filter, the percentile of the merged samples from every
+// matching series sharing that value.
+func (r *Registry) GroupBy(filter LabelSet, groupKey string, percentile float64) (map[string]time.Duration, error) {
+	r.mu.RLock()
+	groups := make(map[string][]sampleSnapshot)
+	for _, s := range r.bySeries {
+		if !s.labels.matches(filter) {
+			continue
+		}
+		v, ok := s.labels.value(groupKey)
+		if !ok {
+			continue
+		}
+		ms, ok := s.svc.(mergeableSeries)
+		if !ok {
+			r.mu.RUnlock()
+			return nil, errors.New("backend does not support group_by")
+		}
+		groups[v] = append(groups[v], ms.snapshot())
+	}
+	r.mu.RUnlock()
+
+	if len(groups) == 0 {
+		return nil, errors.New("no data available")
+	}
+
+	result := make(map[string]time.Duration, len(groups))
+	for value, snaps := range groups {
+		d, err := percentileFromSnapshots(snaps, percentile)
+		if err != nil {
+			return nil, err
+		}
+		result[value] = d
+	}
+	return result, nil
+}
+
+// snapshotter is implemented by backends that support forcing an
+// out-of-band snapshot, such as a persistence-enabled Service.
+type snapshotter interface {
+	Snapshot() error
+}
+
+// Snapshot forces every series whose backend supports it (see
+// snapshotter) to snapshot its current state immediately, and returns how
+// many series were snapshotted. It is used by the /admin/snapshot
+// endpoint to force durability ahead of the configured snapshot interval.
+func (r *Registry) Snapshot() (int, error) {
+	r.mu.RLock()
+	backends := make([]ResponseTimeService, 0, len(r.bySeries))
+	for _, s := range r.bySeries {
+		backends = append(backends, s.svc)
+	}
+	r.mu.RUnlock()
+
+	count := 0
+	for _, svc := range backends {
+		ss, ok := svc.(snapshotter)
+		if !ok {
+			continue
+		}
+		if s, ok := svc.(*Service); ok && s.persist == nil {
+			continue // its Snapshot is a no-op without persistence
+		}
+		if err := ss.Snapshot(); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Close closes every series' backend that implements io.Closer (e.g. a
+// persistence-enabled Service flushing and closing its WAL), for graceful
+// shutdown.
+func (r *Registry) Close() error {
+	r.mu.RLock()
+	backends := make([]ResponseTimeService, 0, len(r.bySeries)+1)
+	for _, s := range r.bySeries {
+		backends = append(backends, s.svc)
+	}
+	backends = append(backends, r.overflow)
+	r.mu.RUnlock()
+
+	for _, svc := range backends {
+		if c, ok := svc.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SeriesInfo describes one label set known to a Registry and how many
+// samples it has recorded.
+type SeriesInfo struct {
+	Labels LabelSet
+	Count  int
+	// WindowOccupied and WindowCapacity describe how many seconds of a
+	// windowed backend's ring buffer currently hold samples, out of its
+	// total capacity. Both are zero for backends that aren't windowed.
+	WindowOccupied int
+	WindowCapacity int
+}
+
+// List returns every known series and its sample count, for introspection
+// endpoints such as /series and /stats.
+func (r *Registry) List() []SeriesInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]SeriesInfo, 0, len(r.bySeries))
+	for _, s := range r.bySeries {
+		stats := s.svc.GetStats()
+		info := SeriesInfo{Labels: s.labels, Count: stats["total_entries"].(int)}
+		if ws, ok := s.svc.(interface{ Occupancy() (int, int) }); ok {
+			info.WindowOccupied, info.WindowCapacity = ws.Occupancy()
+		}
+		out = append(out, info)
+	}
+	return out
+}
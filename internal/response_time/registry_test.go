@@ -0,0 +1,135 @@
+package response_time
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestFactory() seriesFactory {
+	return func(LabelSet) ResponseTimeService {
+		s, _ := NewService()
+		return s
+	}
+}
+
+func TestRegistryStoreAndPercentilePerSeries(t *testing.T) {
+	r := NewRegistry(0, newTestFactory())
+
+	checkout := NewLabelSet(map[string]string{"route": "/checkout"})
+	login := NewLabelSet(map[string]string{"route": "/login"})
+
+	if _, err := r.Store(checkout, time.Now(), 100*time.Millisecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if _, err := r.Store(login, time.Now(), 500*time.Millisecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	got, err := r.Percentile(checkout, 50)
+	if err != nil {
+		t.Fatalf("Percentile: %v", err)
+	}
+	if got != 100*time.Millisecond {
+		t.Errorf("checkout p50 = %v, want 100ms", got)
+	}
+
+	got, err = r.Percentile(login, 50)
+	if err != nil {
+		t.Fatalf("Percentile: %v", err)
+	}
+	if got != 500*time.Millisecond {
+		t.Errorf("login p50 = %v, want 500ms", got)
+	}
+}
+
+func TestRegistryMaxSeriesOverflow(t *testing.T) {
+	r := NewRegistry(1, newTestFactory())
+
+	a := NewLabelSet(map[string]string{"tenant": "acme"})
+	b := NewLabelSet(map[string]string{"tenant": "globex"})
+
+	stored, err := r.Store(a, time.Now(), 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if stored.key() != a.key() {
+		t.Errorf("Store(a) reported labels %v, want %v", stored, a)
+	}
+
+	// b is a second distinct label set after the cap of 1 has been reached,
+	// so it must be folded into the shared overflow series rather than
+	// creating a second real series. Store must report back the overflow
+	// label set, not b, so callers (e.g. recordMetric) don't bypass the cap.
+	stored, err = r.Store(b, time.Now(), 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if stored.key() != overflowLabelSet.key() {
+		t.Errorf("Store(b) reported labels %v, want overflowLabelSet", stored)
+	}
+
+	list := r.List()
+	if len(list) != 1 {
+		t.Fatalf("len(List()) = %d, want 1 (b should have overflowed)", len(list))
+	}
+}
+
+func TestRegistryGroupBy(t *testing.T) {
+	r := NewRegistry(0, newTestFactory())
+
+	acmeCheckout := NewLabelSet(map[string]string{"tenant": "acme", "route": "/checkout"})
+	globexCheckout := NewLabelSet(map[string]string{"tenant": "globex", "route": "/checkout"})
+
+	if _, err := r.Store(acmeCheckout, time.Now(), 100*time.Millisecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if _, err := r.Store(globexCheckout, time.Now(), 100*time.Millisecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	groups, err := r.GroupBy(NewLabelSet(map[string]string{"route": "/checkout"}), "tenant", 50)
+	if err != nil {
+		t.Fatalf("GroupBy: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %d, want 2", len(groups))
+	}
+	for _, tenant := range []string{"acme", "globex"} {
+		if _, ok := groups[tenant]; !ok {
+			t.Errorf("groups missing tenant %q", tenant)
+		}
+	}
+}
+
+func TestRegistrySnapshotAndClose(t *testing.T) {
+	dir := t.TempDir()
+
+	n := 0
+	factory := func(LabelSet) ResponseTimeService {
+		n++
+		s, err := NewService(WithPersistence(fmt.Sprintf("%s/series-%d", dir, n), 1, 1000000))
+		if err != nil {
+			t.Fatalf("NewService: %v", err)
+		}
+		return s
+	}
+
+	r := NewRegistry(0, factory)
+	labels := NewLabelSet(map[string]string{"route": "/checkout"})
+	if _, err := r.Store(labels, time.Now(), 100*time.Millisecond); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	n, err := r.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Snapshot() returned %d, want 1", n)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -9,6 +9,18 @@ import (
 	"time"
 )
 
+// ResponseTimeService is implemented by every storage backend in this
+// package (the slice-backed Service, the bucketed HistogramService and the
+// per-second WindowService) so
+// callers can pick whichever trade-off between precision and memory they
+// need behind a single interface.
+type ResponseTimeService interface {
+	StoreResponseTime(ts time.Time, duration time.Duration) error
+	StoreBatch(entries []ResponseTimeEntry) error
+	GetResponseTime(percentile float64) (time.Duration, error)
+	GetStats() map[string]interface{}
+}
+
 // ResponseTimeEntry represents a single HTTP response time measurement.
 // It contains the timestamp when the request was received and the duration
 // it took to complete the response.
@@ -32,16 +44,60 @@ type Service struct {
 	sortedCache []int64
 	// cacheValid indicates if the sorted cache is up to date
 	cacheValid bool
+	// persist holds the write-ahead-log and snapshot state when
+	// WithPersistence is given to NewService, or nil if persistence is
+	// disabled.
+	persist *persistence
+}
+
+// Option configures optional behavior for NewService.
+type Option func(*Service) error
+
+// WithPersistence enables write-ahead-log and snapshot based durability
+// under dir, so data survives a restart. flushEvery controls how many
+// entries are buffered before the WAL is flushed to disk (1 flushes every
+// entry, trading throughput for durability). snapshotEntries controls how
+// many entries accumulate before the in-memory dataset is snapshotted to
+// snapshot.bin and the WAL is truncated.
+func WithPersistence(dir string, flushEvery, snapshotEntries int) Option {
+	return func(s *Service) error {
+		p, err := newPersistence(dir, flushEvery, snapshotEntries)
+		if err != nil {
+			return err
+		}
+		s.persist = p
+		return nil
+	}
 }
 
-// NewService creates and returns a new Service instance.
-// The returned service is ready to store response times and calculate percentiles.
-func NewService() *Service {
-	return &Service{
+// NewService creates and returns a new Service instance, applying any
+// options given. The returned service is ready to store response times
+// and calculate percentiles. If WithPersistence was given, any data
+// previously made durable under its directory is replayed before
+// NewService returns.
+func NewService(opts ...Option) (*Service, error) {
+	s := &Service{
 		data:        make([]ResponseTimeEntry, 0, 1000), // Pre-allocate for better performance
 		sortedCache: make([]int64, 0, 1000),
 		cacheValid:  true,
 	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.persist != nil {
+		entries, err := s.persist.replay()
+		if err != nil {
+			return nil, err
+		}
+		s.data = append(s.data, entries...)
+		s.cacheValid = false
+	}
+
+	return s, nil
 }
 
 // StoreResponseTime stores a response time entry in memory.
@@ -56,24 +112,78 @@ func NewService() *Service {
 //
 // Example:
 //
-//	service := NewService()
-//	err := service.StoreResponseTime(time.Now(), 150*time.Millisecond)
+//	service, err := NewService()
+//	err = service.StoreResponseTime(time.Now(), 150*time.Millisecond)
 //	if err != nil {
 //	    // handle error
 //	}
 func (s *Service) StoreResponseTime(ts time.Time, duration time.Duration) error {
+	entry := ResponseTimeEntry{Timestamp: ts, Duration: duration}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	entry := ResponseTimeEntry{Timestamp: ts, Duration: duration}
 	s.data = append(s.data, entry)
-
-	// Invalidate cache since we added new data
 	s.cacheValid = false
 
+	return s.afterStoreLocked(entry)
+}
+
+// afterStoreLocked appends entry to the WAL and, once enough entries have
+// accumulated, takes a snapshot. It is a no-op if persistence isn't
+// enabled. Callers must hold s.mu for writing: the data copy a snapshot
+// takes and the WAL truncation it performs must happen without any other
+// store landing in between, or that store's WAL record would be wiped by
+// the truncate without ever being captured in the snapshot.
+func (s *Service) afterStoreLocked(entry ResponseTimeEntry) error {
+	if s.persist == nil {
+		return nil
+	}
+
+	if err := s.persist.append(entry); err != nil {
+		return err
+	}
+	if s.persist.shouldSnapshot() {
+		return s.snapshotLocked()
+	}
 	return nil
 }
 
+// Snapshot immediately writes the current dataset to snapshot.bin and
+// truncates the WAL, regardless of how many entries have accumulated
+// since the last automatic snapshot. It is a no-op if persistence isn't
+// enabled.
+func (s *Service) Snapshot() error {
+	if s.persist == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.snapshotLocked()
+}
+
+// snapshotLocked does the work of Snapshot. Callers must hold s.mu for
+// writing so that the copy of s.data and the WAL truncate it feeds into
+// persist.snapshot are atomic with respect to concurrent stores.
+func (s *Service) snapshotLocked() error {
+	durations := make([]int64, len(s.data))
+	for i, e := range s.data {
+		durations[i] = int64(e.Duration)
+	}
+	return s.persist.snapshot(durations)
+}
+
+// Close fsyncs and closes the write-ahead log. It is a no-op if
+// persistence isn't enabled.
+func (s *Service) Close() error {
+	if s.persist == nil {
+		return nil
+	}
+	return s.persist.Close()
+}
+
 // GetResponseTime returns the response time at the given percentile.
 // The percentile must be between 0 and 100 (inclusive).
 // For example, 90 returns the 90th percentile response time.
@@ -129,6 +239,31 @@ func (s *Service) GetResponseTime(percentile float64) (time.Duration, error) {
 	return result, nil
 }
 
+// StoreBatch records every entry in one call, taking the write lock once
+// instead of once per entry. This is the primitive the /store/bulk HTTP
+// endpoint and the middleware subpackage use to avoid lock contention when
+// ingesting many samples at once.
+func (s *Service) StoreBatch(entries []ResponseTimeEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = append(s.data, entries...)
+	s.cacheValid = false
+
+	if s.persist == nil {
+		return nil
+	}
+	for _, e := range entries {
+		if err := s.persist.append(e); err != nil {
+			return err
+		}
+	}
+	if s.persist.shouldSnapshot() {
+		return s.snapshotLocked()
+	}
+	return nil
+}
+
 // rebuildCache rebuilds the sorted cache for faster percentile queries.
 // This is called when new data is added and the cache becomes invalid.
 func (s *Service) rebuildCache() {
@@ -167,3 +302,102 @@ func (s *Service) GetStats() map[string]interface{} {
 		"cache_size":    len(s.sortedCache),
 	}
 }
+
+// mergeableSeries is implemented by backends that can expose their samples
+// in a mergeable form, which Registry.GroupBy needs in order to combine
+// several series into one percentile query.
+type mergeableSeries interface {
+	snapshot() sampleSnapshot
+}
+
+// sampleSnapshot holds one backend's samples in whatever native
+// representation it keeps them in. Exactly one of durations or counts is
+// populated, depending on which backend produced the snapshot.
+type sampleSnapshot struct {
+	// durations holds raw, unsorted sample values; populated by Service.
+	durations []int64
+	// counts holds HDR bucket counters; populated by HistogramService.
+	counts []int64
+	// valueFromIdx turns a counts index back into a duration. Only set
+	// alongside counts.
+	valueFromIdx func(int) int64
+}
+
+// snapshot returns a copy of every duration currently stored, for merging
+// with other series in percentileFromSnapshots.
+func (s *Service) snapshot() sampleSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	durations := make([]int64, len(s.data))
+	for i, entry := range s.data {
+		durations[i] = int64(entry.Duration)
+	}
+	return sampleSnapshot{durations: durations}
+}
+
+// percentileFromSnapshots merges several snapshots produced by the same
+// backend kind and computes a percentile over the combined samples. All
+// snapshots passed in a single call must come from the same kind of
+// backend (all durations or all counts of equal length).
+func percentileFromSnapshots(snaps []sampleSnapshot, percentile float64) (time.Duration, error) {
+	if percentile < 0 || percentile > 100 {
+		return 0, errors.New("percentile must be between 0 and 100")
+	}
+
+	if len(snaps) > 0 && snaps[0].counts != nil {
+		return percentileFromCountSnapshots(snaps, percentile)
+	}
+
+	var all []int64
+	for _, snap := range snaps {
+		all = append(all, snap.durations...)
+	}
+	if len(all) == 0 {
+		return 0, errors.New("no data available")
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	idx := int((percentile/100.0)*float64(len(all))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(all) {
+		idx = len(all) - 1
+	}
+	return time.Duration(all[idx]), nil
+}
+
+// percentileFromCountSnapshots merges several HDR bucket-counter snapshots
+// elementwise and walks the merged buckets the same way
+// HistogramService.GetResponseTime does.
+func percentileFromCountSnapshots(snaps []sampleSnapshot, percentile float64) (time.Duration, error) {
+	merged := make([]int64, len(snaps[0].counts))
+	var total int64
+	for _, snap := range snaps {
+		if len(snap.counts) != len(merged) {
+			return 0, errors.New("cannot merge histograms with different bucket layouts")
+		}
+		for i, c := range snap.counts {
+			merged[i] += c
+			total += c
+		}
+	}
+	if total == 0 {
+		return 0, errors.New("no data available")
+	}
+
+	target := int64(percentile / 100.0 * float64(total))
+	if target < 1 {
+		target = 1
+	}
+
+	var cumulative int64
+	for idx, c := range merged {
+		cumulative += c
+		if cumulative >= target {
+			return time.Duration(snaps[0].valueFromIdx(idx)), nil
+		}
+	}
+	return time.Duration(snaps[0].valueFromIdx(len(merged) - 1)), nil
+}
@@ -0,0 +1,223 @@
+package response_time
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// WindowService answers "last N" percentile queries (p99 over the last
+// minute, last 5 minutes, ...) instead of only over a process's entire
+// history. It keeps a ring buffer of per-second histograms, advances the
+// head to the current second on every store, and merges however many
+// trailing seconds a query asks for.
+//
+// WindowService is safe for concurrent use.
+type WindowService struct {
+	mu sync.Mutex
+
+	newBucket func() *HistogramService
+
+	buckets      []*HistogramService // ring buffer, one per tracked second
+	bucketSecond []int64             // unix second each buckets[i] currently holds
+	head         int64               // unix second of the most recently advanced slot
+	started      bool
+
+	// overflow absorbs samples whose timestamp falls outside the ring,
+	// either because it is older than the oldest tracked second (clock
+	// skew / a slow producer) or because the ring hasn't reached that
+	// second yet.
+	overflow      *HistogramService
+	overflowCount int64
+}
+
+// NewWindowService creates a WindowService that tracks the trailing
+// seconds seconds of history. newBucket must always return a histogram
+// with the same (min, max, significantDigits) configuration, since merging
+// two histograms with different bucket layouts is not possible.
+func NewWindowService(seconds int, newBucket func() *HistogramService) (*WindowService, error) {
+	if seconds <= 0 {
+		return nil, errors.New("seconds must be positive")
+	}
+
+	buckets := make([]*HistogramService, seconds)
+	for i := range buckets {
+		buckets[i] = newBucket()
+	}
+
+	return &WindowService{
+		newBucket:    newBucket,
+		buckets:      buckets,
+		bucketSecond: make([]int64, seconds),
+		overflow:     newBucket(),
+	}, nil
+}
+
+// secondIndex returns the ring slot for unix second sec.
+func (w *WindowService) secondIndex(sec int64) int {
+	n := int64(len(w.buckets))
+	idx := sec % n
+	if idx < 0 {
+		idx += n
+	}
+	return int(idx)
+}
+
+// advanceLocked moves the head forward to sec, zeroing every slot it
+// passes through. Callers must hold w.mu.
+func (w *WindowService) advanceLocked(sec int64) {
+	if !w.started {
+		w.buckets[w.secondIndex(sec)] = w.newBucket()
+		w.bucketSecond[w.secondIndex(sec)] = sec
+		w.head = sec
+		w.started = true
+		return
+	}
+
+	for s := w.head + 1; s <= sec; s++ {
+		idx := w.secondIndex(s)
+		w.buckets[idx] = w.newBucket()
+		w.bucketSecond[idx] = s
+	}
+	w.head = sec
+}
+
+// StoreResponseTime records duration into the per-second bucket for ts. A
+// timestamp older than the tracked window (clock skew, or a sample
+// delivered well after the fact) is recorded into an overflow bucket
+// instead of being dropped silently.
+func (w *WindowService) StoreResponseTime(ts time.Time, duration time.Duration) error {
+	sec := ts.Truncate(time.Second).Unix()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// A forward jump bigger than the ring's own capacity would evict every
+	// second of real history just to make room for one sample (e.g. a
+	// bogus or malicious far-future timestamp), advancing head to that
+	// point and permanently routing subsequent real-time stores into
+	// overflow instead. Treat a jump that large the same as any other
+	// sample the ring can't currently hold, without ever moving head or
+	// allocating the buckets in between.
+	if w.started && sec > w.head && sec-w.head > int64(len(w.buckets)) {
+		w.overflowCount++
+		return w.overflow.StoreResponseTime(ts, duration)
+	}
+
+	if !w.started || sec > w.head {
+		w.advanceLocked(sec)
+	}
+
+	oldest := w.head - int64(len(w.buckets)) + 1
+	if sec < oldest {
+		w.overflowCount++
+		return w.overflow.StoreResponseTime(ts, duration)
+	}
+
+	idx := w.secondIndex(sec)
+	if w.bucketSecond[idx] != sec {
+		// The slot has since been recycled for a newer second; sec fell
+		// just outside the window by the time we got here.
+		w.overflowCount++
+		return w.overflow.StoreResponseTime(ts, duration)
+	}
+
+	return w.buckets[idx].StoreResponseTime(ts, duration)
+}
+
+// StoreBatch records every entry by calling StoreResponseTime in order.
+// Unlike Service.StoreBatch, each entry still advances the ring and takes
+// its own lock individually, since which bucket (and whether the ring
+// needs to advance) depends on each entry's own timestamp.
+func (w *WindowService) StoreBatch(entries []ResponseTimeEntry) error {
+	for _, e := range entries {
+		if err := w.StoreResponseTime(e.Timestamp, e.Duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetResponseTime returns the response time at percentile over the entire
+// tracked window (the longest window this service can answer).
+func (w *WindowService) GetResponseTime(percentile float64) (time.Duration, error) {
+	return w.GetResponseTimeWindow(percentile, time.Duration(len(w.buckets))*time.Second)
+}
+
+// GetResponseTimeWindow returns the response time at percentile over the
+// trailing window duration, merging the per-second histograms it covers.
+// window is clamped to however much history the ring actually tracks.
+func (w *WindowService) GetResponseTimeWindow(percentile float64, window time.Duration) (time.Duration, error) {
+	seconds := int64(window / time.Second)
+	if seconds <= 0 {
+		seconds = 1
+	}
+	if seconds > int64(len(w.buckets)) {
+		seconds = int64(len(w.buckets))
+	}
+
+	w.mu.Lock()
+	if !w.started {
+		w.mu.Unlock()
+		return 0, errors.New("no data available")
+	}
+
+	snaps := make([]sampleSnapshot, 0, seconds)
+	for s := w.head - seconds + 1; s <= w.head; s++ {
+		idx := w.secondIndex(s)
+		if w.bucketSecond[idx] != s {
+			continue // that second never received any samples
+		}
+		snaps = append(snaps, w.buckets[idx].snapshot())
+	}
+	w.mu.Unlock()
+
+	if len(snaps) == 0 {
+		return 0, errors.New("no data available in window")
+	}
+
+	return percentileFromSnapshots(snaps, percentile)
+}
+
+// Occupancy reports how many of the ring's seconds currently hold at least
+// one sample, and the ring's total capacity in seconds. It is exposed
+// through /stats so operators can tell a quiet window apart from a broken
+// one.
+func (w *WindowService) Occupancy() (occupied, capacity int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.started {
+		return 0, len(w.buckets)
+	}
+
+	oldest := w.head - int64(len(w.buckets)) + 1
+	for s := oldest; s <= w.head; s++ {
+		idx := w.secondIndex(s)
+		if w.bucketSecond[idx] == s {
+			occupied++
+		}
+	}
+	return occupied, len(w.buckets)
+}
+
+// GetStats returns basic statistics about the window, mirroring the shape
+// returned by the other backends' GetStats so callers can treat every
+// backend interchangeably.
+func (w *WindowService) GetStats() map[string]interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var total int64
+	for _, b := range w.buckets {
+		stats := b.GetStats()
+		total += int64(stats["total_entries"].(int))
+	}
+
+	return map[string]interface{}{
+		"total_entries":  int(total),
+		"cache_valid":    true,
+		"cache_size":     len(w.buckets),
+		"overflow_count": int(w.overflowCount),
+	}
+}
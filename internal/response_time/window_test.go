@@ -0,0 +1,118 @@
+package response_time
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestWindow(t *testing.T, seconds int) *WindowService {
+	t.Helper()
+	w, err := NewWindowService(seconds, func() *HistogramService {
+		hs, err := NewHistogramService(time.Microsecond, 10*time.Second, 3)
+		if err != nil {
+			t.Fatalf("NewHistogramService: %v", err)
+		}
+		return hs
+	})
+	if err != nil {
+		t.Fatalf("NewWindowService: %v", err)
+	}
+	return w
+}
+
+func TestWindowServiceStoreAndQuery(t *testing.T) {
+	w := newTestWindow(t, 60)
+	now := time.Now()
+
+	for i, ms := range []int64{100, 200, 300} {
+		ts := now.Add(time.Duration(i) * time.Second)
+		if err := w.StoreResponseTime(ts, time.Duration(ms)*time.Millisecond); err != nil {
+			t.Fatalf("StoreResponseTime: %v", err)
+		}
+	}
+
+	p, err := w.GetResponseTimeWindow(100, 5*time.Second)
+	if err != nil {
+		t.Fatalf("GetResponseTimeWindow: %v", err)
+	}
+	if p < 290*time.Millisecond {
+		t.Errorf("p100 = %v, want at least ~300ms", p)
+	}
+}
+
+func TestWindowServiceOldTimestampGoesToOverflow(t *testing.T) {
+	w := newTestWindow(t, 5)
+	now := time.Now()
+
+	if err := w.StoreResponseTime(now, 100*time.Millisecond); err != nil {
+		t.Fatalf("StoreResponseTime: %v", err)
+	}
+	// Advance the ring by exactly its 5-second capacity (a larger single
+	// jump would go to overflow itself without moving the ring) so the next
+	// store's timestamp is older than anything the ring still tracks.
+	if err := w.StoreResponseTime(now.Add(5*time.Second), 100*time.Millisecond); err != nil {
+		t.Fatalf("StoreResponseTime: %v", err)
+	}
+	if err := w.StoreResponseTime(now, 999*time.Millisecond); err != nil {
+		t.Fatalf("StoreResponseTime (stale): %v", err)
+	}
+
+	stats := w.GetStats()
+	if got := stats["overflow_count"]; got != 1 {
+		t.Errorf("overflow_count = %v, want 1", got)
+	}
+}
+
+func TestWindowServiceFarFutureTimestampGoesToOverflow(t *testing.T) {
+	w := newTestWindow(t, 5)
+	now := time.Now()
+
+	if err := w.StoreResponseTime(now, 100*time.Millisecond); err != nil {
+		t.Fatalf("StoreResponseTime: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.StoreResponseTime(now.Add(100*365*24*time.Hour), 100*time.Millisecond)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("StoreResponseTime (far future): %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StoreResponseTime (far future) did not return in time, want it to be bounded by ring capacity")
+	}
+
+	// A future sample beyond the ring's capacity must not move head, so a
+	// normal, present-day store right after it must still land in the ring
+	// rather than also being folded into overflow.
+	if err := w.StoreResponseTime(now.Add(time.Second), 200*time.Millisecond); err != nil {
+		t.Fatalf("StoreResponseTime (present): %v", err)
+	}
+
+	stats := w.GetStats()
+	if got := stats["overflow_count"]; got != 1 {
+		t.Errorf("overflow_count = %v, want 1 (only the far-future sample)", got)
+	}
+}
+
+func TestWindowServiceOccupancy(t *testing.T) {
+	w := newTestWindow(t, 10)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i) * time.Second)
+		if err := w.StoreResponseTime(ts, time.Millisecond); err != nil {
+			t.Fatalf("StoreResponseTime: %v", err)
+		}
+	}
+
+	occupied, capacity := w.Occupancy()
+	if capacity != 10 {
+		t.Errorf("capacity = %d, want 10", capacity)
+	}
+	if occupied != 3 {
+		t.Errorf("occupied = %d, want 3", occupied)
+	}
+}